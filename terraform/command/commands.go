@@ -0,0 +1,21 @@
+package command
+
+import (
+	"github.com/mitchellh/cli"
+)
+
+// Commands returns the CommandFactory map the CLI entrypoint uses to look up
+// a subcommand by name. It's built from meta so that every command shares
+// the same Ui/working-directory plumbing.
+//
+// This only lists the commands that exist in this tree; the rest of the
+// real entrypoint's map (apply, plan, init, and so on) is assembled the same
+// way, just with each command's own Meta-embedding struct in place of
+// QueryCommand.
+func Commands(meta Meta) map[string]cli.CommandFactory {
+	return map[string]cli.CommandFactory{
+		"query": func() (cli.Command, error) {
+			return &QueryCommand{Meta: meta}, nil
+		},
+	}
+}
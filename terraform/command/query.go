@@ -0,0 +1,132 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/tfquery"
+)
+
+// noopSchemas is a typed.ProviderSchemas that knows no schemas at all. It's
+// used when querying state without plugins available to load real provider
+// schemas from; every instance's Value is then cty.DynamicVal, which shows
+// up as null in the query AST instead of a typed tree.
+type noopSchemas struct{}
+
+func (noopSchemas) SchemaForResourceType(resourceType string) *configschema.Block {
+	return nil
+}
+
+// QueryCommand is a Command implementation that runs a jq-style expression
+// against a Terraform config directory (default) or state file.
+//
+// It shares the same Meta embedding as the other commands in this package
+// for UI/working-directory plumbing; see command.go for Meta's fields.
+type QueryCommand struct {
+	Meta
+}
+
+func (c *QueryCommand) Run(args []string) int {
+	args = c.Meta.process(args, false)
+
+	var state string
+	var write bool
+	var useJQ bool
+
+	cmdFlags := flag.NewFlagSet("query", flag.ContinueOnError)
+	cmdFlags.StringVar(&state, "state", "", "path to a terraform.tfstate file to query instead of config")
+	cmdFlags.BoolVar(&write, "w", false, "rewrite the matched config attributes in place instead of printing results")
+	cmdFlags.BoolVar(&useJQ, "jq", false, "shell out to the jq binary on PATH instead of the embedded evaluator")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The query command expects exactly one argument: the jq expression to run.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+	expr := args[0]
+
+	configPath, err := ModulePath(nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if write {
+		if state != "" {
+			c.Ui.Error("-w can only be used to rewrite configuration, not state")
+			return 1
+		}
+		if err := tfquery.Rewrite(configPath, expr); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error rewriting config: %s", err))
+			return 1
+		}
+		return 0
+	}
+
+	var ast map[string]interface{}
+	if state != "" {
+		f, err := os.Open(state)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error opening state: %s", err))
+			return 1
+		}
+		defer f.Close()
+
+		ast, err = tfquery.LoadStateReader(f, noopSchemas{})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error loading state: %s", err))
+			return 1
+		}
+	} else {
+		ast, err = tfquery.LoadConfigDir(configPath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error loading config: %s", err))
+			return 1
+		}
+	}
+
+	results, err := tfquery.Eval(ast, expr, useJQ)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error evaluating query: %s", err))
+		return 1
+	}
+
+	for _, r := range results {
+		c.Ui.Output(fmt.Sprintf("%v", r))
+	}
+
+	return 0
+}
+
+func (c *QueryCommand) Help() string {
+	helpText := `
+Usage: terraform query [options] EXPRESSION
+
+  Runs a jq expression against this configuration's AST (blocks, labels,
+  and attributes), or against a state file's typed resource instances
+  with -state.
+
+Options:
+
+  -state=path   Query a terraform.tfstate file instead of configuration.
+
+  -w            Rewrite matched configuration attributes in place instead
+                of printing query results. Only valid against config.
+
+  -jq           Shell out to the jq binary on PATH instead of using the
+                embedded evaluator.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *QueryCommand) Synopsis() string {
+	return "Run a jq expression against config or state"
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+)
+
+// Cloud is the configuration for a "cloud" block nested inside "terraform".
+// It's equivalent to "backend \"cloud\" { ... }" but with its own fields
+// instead of a generic opaque body, since every cloud configuration shares
+// the same handful of settings.
+type Cloud struct {
+	Organization string
+	Hostname     string
+	Workspaces   *CloudWorkspaces
+
+	// Pos is the source position of the "cloud" block, for diagnostics
+	// that need to point back at it (e.g. the backend/cloud conflict
+	// check in the terraform block loader). This package parses with
+	// HCL v1, which has no hcl2-style Range, just a start token.Pos.
+	Pos token.Pos
+
+	// rawConfig holds the whole decoded block (organization, hostname,
+	// workspaces) as a RawConfig. Nothing in this package reads it;
+	// Backend builds its own RawConfig from the typed fields above.
+	rawConfig *RawConfig
+}
+
+// CloudWorkspaces is the "workspaces" sub-block of a "cloud" block. Exactly
+// one of Name, Tags, or Project is expected to be set.
+type CloudWorkspaces struct {
+	Name    string
+	Tags    []string
+	Project string
+}
+
+// Backend translates a Cloud configuration into the Backend shape the rest
+// of the backend-init pipeline already understands, tagging it with the
+// reserved type name "cloud". No client is registered under that name in
+// remote.BuiltinClients, so remote.NewClientFromBackend returns an
+// "unknown remote state backend type" error for the result.
+func (c *Cloud) Backend() (*Backend, error) {
+	rawValues := map[string]interface{}{
+		"organization": c.Organization,
+		"hostname":     c.Hostname,
+	}
+	if c.Workspaces != nil {
+		rawValues["workspaces"] = map[string]interface{}{
+			"name":    c.Workspaces.Name,
+			"project": c.Workspaces.Project,
+			"tags":    c.Workspaces.Tags,
+		}
+	}
+
+	raw, err := NewRawConfig(rawValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build backend config for cloud block: %s", err)
+	}
+
+	return &Backend{
+		Type:      "cloud",
+		RawConfig: raw,
+	}, nil
+}
+
+// decodeCloudBlock decodes a "cloud" block's ast.ObjectItem into a Cloud.
+// It's called from loadTerraformHcl alongside loadTerraformBackendHcl; the
+// two are mutually exclusive, which the caller is responsible for enforcing
+// (via validateBackendCloudExclusive).
+func decodeCloudBlock(item *hclast.ObjectItem) (*Cloud, error) {
+	var raw map[string]interface{}
+	if err := hcl.DecodeObject(&raw, item.Val); err != nil {
+		return nil, fmt.Errorf("error reading cloud config: %s", err)
+	}
+
+	cloud := &Cloud{Pos: item.Pos()}
+	if v, ok := raw["organization"].(string); ok {
+		cloud.Organization = v
+	}
+	if v, ok := raw["hostname"].(string); ok {
+		cloud.Hostname = v
+	}
+
+	if rawWorkspaces, ok := raw["workspaces"]; ok {
+		ws, err := decodeCloudWorkspaces(rawWorkspaces)
+		if err != nil {
+			return nil, err
+		}
+		cloud.Workspaces = ws
+	}
+
+	rawConfig, err := NewRawConfig(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cloud config: %s", err)
+	}
+	cloud.rawConfig = rawConfig
+
+	return cloud, nil
+}
+
+// validateBackendCloudExclusive enforces that a "terraform" block doesn't
+// configure both "backend" and "cloud" at once; the loader calls this once
+// it has decoded both (or either). The error cites the position of the
+// "cloud" block only; Backend carries no source position.
+func validateBackendCloudExclusive(backend *Backend, cloud *Cloud) error {
+	if backend != nil && cloud != nil {
+		return fmt.Errorf(
+			"only one of \"backend\" or \"cloud\" may be set in a \"terraform\" block, not both (cloud block at %s)",
+			cloud.Pos)
+	}
+	return nil
+}
+
+func decodeCloudWorkspaces(raw interface{}) (*CloudWorkspaces, error) {
+	var list []map[string]interface{}
+	switch v := raw.(type) {
+	case []map[string]interface{}:
+		list = v
+	case map[string]interface{}:
+		list = []map[string]interface{}{v}
+	default:
+		return nil, fmt.Errorf("workspaces block has unexpected type %T", raw)
+	}
+	if len(list) != 1 {
+		return nil, fmt.Errorf("only one workspaces block is allowed in a cloud block")
+	}
+
+	m := list[0]
+	ws := &CloudWorkspaces{}
+
+	if v, ok := m["name"].(string); ok {
+		ws.Name = v
+	}
+	if v, ok := m["project"].(string); ok {
+		ws.Project = v
+	}
+	if v, ok := m["tags"].([]interface{}); ok {
+		for _, t := range v {
+			s, ok := t.(string)
+			if !ok {
+				return nil, fmt.Errorf("workspaces.tags must be a list of strings")
+			}
+			ws.Tags = append(ws.Tags, s)
+		}
+	}
+
+	set := 0
+	for _, v := range []string{ws.Name, ws.Project} {
+		if v != "" {
+			set++
+		}
+	}
+	if len(ws.Tags) > 0 {
+		set++
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("workspaces block must set exactly one of name, tags, or project")
+	}
+
+	return ws, nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+)
+
+func TestDecodeCloudBlock(t *testing.T) {
+	src := `
+cloud {
+  organization = "my-org"
+  hostname     = "app.terraform.io"
+
+  workspaces {
+    tags = ["networking", "prod"]
+  }
+}
+`
+	f, err := hcl.Parse(src)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	list := f.Node.(*hclast.ObjectList)
+	items := list.Filter("cloud").Items
+	if len(items) != 1 {
+		t.Fatalf("expected 1 cloud block, got %d", len(items))
+	}
+
+	cloud, err := decodeCloudBlock(items[0])
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if cloud.Organization != "my-org" {
+		t.Fatalf("wrong organization: %q", cloud.Organization)
+	}
+	if cloud.Hostname != "app.terraform.io" {
+		t.Fatalf("wrong hostname: %q", cloud.Hostname)
+	}
+	if cloud.Workspaces == nil || len(cloud.Workspaces.Tags) != 2 {
+		t.Fatalf("wrong workspaces: %#v", cloud.Workspaces)
+	}
+}
+
+func TestCloudBackend_workspaces(t *testing.T) {
+	cloud := &Cloud{
+		Organization: "my-org",
+		Hostname:     "app.terraform.io",
+		Workspaces: &CloudWorkspaces{
+			Tags: []string{"networking", "prod"},
+		},
+	}
+
+	backend, err := cloud.Backend()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if backend.Type != "cloud" {
+		t.Fatalf("wrong type: %q", backend.Type)
+	}
+
+	raw := backend.RawConfig.Config()
+	workspaces, ok := raw["workspaces"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("workspaces missing from translated backend config: %#v", raw)
+	}
+	tags, ok := workspaces["tags"].([]string)
+	if !ok || len(tags) != 2 {
+		t.Fatalf("wrong workspaces.tags: %#v", workspaces["tags"])
+	}
+}
+
+func TestValidateBackendCloudExclusive(t *testing.T) {
+	if err := validateBackendCloudExclusive(&Backend{Type: "s3"}, &Cloud{}); err == nil {
+		t.Fatal("expected error when both backend and cloud are set")
+	}
+	if err := validateBackendCloudExclusive(nil, &Cloud{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := validateBackendCloudExclusive(&Backend{Type: "s3"}, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"hash/crc32"
+)
+
+// Terraform is the configuration of a "terraform" block: the handful of
+// settings that configure Terraform itself, as opposed to a provider or
+// resource.
+type Terraform struct {
+	RequiredVersion string // Required Terraform version (constraint)
+
+	// Backend and Cloud are mutually exclusive ways of telling Terraform
+	// where to store its state; validateBackendCloudExclusive enforces
+	// that only one of the two is set.
+	Backend *Backend
+	Cloud   *Cloud
+}
+
+// Backend is the configuration for a "backend" block nested inside
+// "terraform", telling Terraform which state backend to use and how to
+// configure it.
+type Backend struct {
+	Type      string
+	RawConfig *RawConfig
+
+	// Hash is a checksum of the backend configuration, used to detect when
+	// "terraform init" needs to reconfigure the backend because its
+	// settings changed since the last run.
+	Hash int
+}
+
+// Rehash returns a unique hash for this backend configuration.
+func (b *Backend) Rehash() int {
+	if b == nil {
+		return 0
+	}
+
+	return int(crc32.ChecksumIEEE([]byte(fmt.Sprintf(
+		"%s %#v", b.Type, b.RawConfig.Config()))))
+}
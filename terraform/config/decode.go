@@ -0,0 +1,295 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
+
+	"github.com/hashicorp/terraform/configs/hcl2shim"
+)
+
+// DecodeInto decodes the raw configuration directly into target, a pointer
+// to a struct, using the same struct tags as gohcl.DecodeBody:
+//
+//	type Versioning struct {
+//	    Enabled bool `hcl:"enabled,attr"`
+//	}
+//
+//	type Bucket struct {
+//	    Name       string                `hcl:"name,attr"`
+//	    Versioning []Versioning          `hcl:"versioning,block"`
+//	    Tags       map[string]string     `hcl:"tags,attr"`
+//	}
+//
+// Unlike RawConfig.Get, which only ever returns flattened dotted keys
+// ("versioning.0.enabled", "tags.w"), DecodeInto walks nested
+// maps/slices/blocks directly into the target struct, including maps of
+// objects and repeated blocks decoded as a slice. This gives provider
+// authors roughly the same ergonomics in Create/Update that gohcl.DecodeBody
+// gives config/module authors, without hand-walking d.Get paths.
+//
+// An "attr" field may also carry a `cty:"name"` tag; its value is converted
+// with gocty instead of mapstructure's weak typing, which matters for
+// anything gocty treats specially - a destination field typed cty.Value
+// itself to defer interpreting the value at all, or a numeric field that
+// should reject a non-numeric string rather than silently zeroing it.
+func (c *RawConfig) DecodeInto(target interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          "hcl",
+		WeaklyTypedInput: true,
+		Result:           target,
+	})
+	if err != nil {
+		return fmt.Errorf("config: failed to build decoder: %s", err)
+	}
+
+	raw := c.Config()
+
+	// cty-tagged fields are decoded separately below, via gocty rather than
+	// mapstructure. Strip their keys out of the copy mapstructure sees so it
+	// never has to decode, say, a plain string into a cty.Value struct.
+	stripped := stripCtyTaggedKeys(reflect.TypeOf(target), raw)
+	if err := decoder.Decode(stripped); err != nil {
+		return err
+	}
+
+	return decodeCtyTags(reflect.ValueOf(target), raw)
+}
+
+// stripCtyTaggedKeys returns a copy of raw with the config keys belonging to
+// any cty-tagged attr field (found by walking t, target's type) removed,
+// recursing into block fields the same way mapstructure itself would.
+func stripCtyTaggedKeys(t reflect.Type, raw map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return raw
+	}
+
+	cleaned := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		cleaned[k] = v
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, kind, ok := hclTag(field)
+		if !ok {
+			continue
+		}
+
+		if _, hasCty := field.Tag.Lookup("cty"); hasCty {
+			delete(cleaned, name)
+			continue
+		}
+
+		if kind != "block" {
+			continue
+		}
+		if rawValue, present := cleaned[name]; present {
+			cleaned[name] = stripCtyTaggedKeysInBlock(field.Type, rawValue)
+		}
+	}
+
+	return cleaned
+}
+
+// stripCtyTaggedKeysInBlock applies stripCtyTaggedKeys to a block field's
+// raw value, which mapstructure may decode as a single struct, a slice of
+// structs (repeated blocks), or a map of structs (blocks keyed by label).
+func stripCtyTaggedKeysInBlock(fieldType reflect.Type, rawValue interface{}) interface{} {
+	switch fieldType.Kind() {
+	case reflect.Slice:
+		rawList, ok := rawValue.([]map[string]interface{})
+		if !ok {
+			return rawValue
+		}
+		cleaned := make([]map[string]interface{}, len(rawList))
+		for i, item := range rawList {
+			cleaned[i] = stripCtyTaggedKeys(fieldType.Elem(), item)
+		}
+		return cleaned
+	case reflect.Map:
+		rawMap, ok := rawValue.(map[string]interface{})
+		if !ok {
+			return rawValue
+		}
+		cleaned := make(map[string]interface{}, len(rawMap))
+		for k, v := range rawMap {
+			if item, ok := v.(map[string]interface{}); ok {
+				cleaned[k] = stripCtyTaggedKeys(fieldType.Elem(), item)
+			} else {
+				cleaned[k] = v
+			}
+		}
+		return cleaned
+	case reflect.Struct, reflect.Ptr:
+		rawMap, ok := rawValue.(map[string]interface{})
+		if !ok {
+			return rawValue
+		}
+		return stripCtyTaggedKeys(fieldType, rawMap)
+	default:
+		return rawValue
+	}
+}
+
+// decodeCtyTags makes a second pass over target (which mapstructure has
+// already populated via the "hcl" tags, skipping anything cty-tagged) and
+// decodes, via gocty, any field that carries a `cty:"..."` tag. It mirrors
+// target's struct shape against raw to find each such field's source value,
+// recursing into nested blocks the same way mapstructure did on the first
+// pass.
+func decodeCtyTags(target reflect.Value, raw map[string]interface{}) error {
+	for target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			return nil
+		}
+		target = target.Elem()
+	}
+	if target.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typ := target.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, kind, ok := hclTag(field)
+		if !ok {
+			continue
+		}
+
+		rawValue, present := raw[name]
+		if !present {
+			continue
+		}
+
+		fieldValue := target.Field(i)
+
+		if _, hasCty := field.Tag.Lookup("cty"); hasCty {
+			if kind == "block" {
+				return fmt.Errorf("field %s: a cty tag is only valid on an attr field, not a block", field.Name)
+			}
+			if !fieldValue.CanAddr() {
+				return fmt.Errorf("field %s: cty-tagged fields must be addressable", field.Name)
+			}
+			if err := decodeCtyLeaf(fieldValue, rawValue); err != nil {
+				return fmt.Errorf("field %s: %s", field.Name, err)
+			}
+			continue
+		}
+
+		if kind != "block" {
+			continue
+		}
+
+		if err := decodeCtyTagsInBlocks(fieldValue, rawValue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeCtyLeaf converts rawValue into fieldValue's native Go type via
+// gocty. hcl2shim only knows how to produce a cty.Value shaped like the raw
+// Go value it was given (so a numeric RawConfig string like "3" becomes
+// cty.StringVal("3"), not a number) - convert.Convert bridges that gap using
+// fieldValue's implied cty.Type, the same way a "3" attribute read as a
+// string in legacy HCL1 config ends up converted to a number elsewhere.
+func decodeCtyLeaf(fieldValue reflect.Value, rawValue interface{}) error {
+	wantType, err := gocty.ImpliedType(fieldValue.Interface())
+	if err != nil {
+		return fmt.Errorf("unsupported cty-tagged type: %s", err)
+	}
+
+	ctyVal, err := convert.Convert(hcl2shim.HCL2ValueFromConfigValue(rawValue), wantType)
+	if err != nil {
+		return err
+	}
+
+	return gocty.FromCtyValue(ctyVal, fieldValue.Addr().Interface())
+}
+
+// decodeCtyTagsInBlocks recurses decodeCtyTags into a block field, which
+// mapstructure may have populated as a single struct, a slice of structs
+// (repeated blocks), or a map of structs (blocks keyed by label).
+func decodeCtyTagsInBlocks(fieldValue reflect.Value, rawValue interface{}) error {
+	switch fieldValue.Kind() {
+	case reflect.Slice:
+		rawList, ok := rawValue.([]map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i := 0; i < fieldValue.Len() && i < len(rawList); i++ {
+			if err := decodeCtyTags(fieldValue.Index(i).Addr(), rawList[i]); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		rawMap, ok := rawValue.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range fieldValue.MapKeys() {
+			rawItem, ok := rawMap[fmt.Sprintf("%v", key.Interface())].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			elem := fieldValue.MapIndex(key)
+			if elem.Kind() == reflect.Ptr {
+				if err := decodeCtyTags(elem, rawItem); err != nil {
+					return err
+				}
+			} else if elem.Kind() == reflect.Struct {
+				// Map elements aren't addressable, so decode into a copy
+				// and store it back.
+				cp := reflect.New(elem.Type()).Elem()
+				cp.Set(elem)
+				if err := decodeCtyTags(cp.Addr(), rawItem); err != nil {
+					return err
+				}
+				fieldValue.SetMapIndex(key, cp)
+			}
+		}
+	case reflect.Struct:
+		rawMap, ok := rawValue.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if err := decodeCtyTags(fieldValue.Addr(), rawMap); err != nil {
+			return err
+		}
+	case reflect.Ptr:
+		rawMap, ok := rawValue.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if err := decodeCtyTags(fieldValue, rawMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hclTag parses a field's "hcl" tag into its config key name and its kind
+// ("attr" or "block"), matching the format gohcl.DecodeBody uses.
+func hclTag(field reflect.StructField) (name, kind string, ok bool) {
+	tag, ok := field.Tag.Lookup("hcl")
+	if !ok || tag == "" {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(tag, ",", 2)
+	name = parts[0]
+	if len(parts) == 2 {
+		kind = parts[1]
+	}
+	return name, kind, true
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestRawConfigDecodeInto(t *testing.T) {
+	raw, err := NewRawConfig(map[string]interface{}{
+		"name": "my-bucket",
+		"versioning": []map[string]interface{}{
+			{"enabled": true},
+		},
+		"tags": map[string]interface{}{
+			"Env": "prod",
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	type Versioning struct {
+		Enabled bool `hcl:"enabled,attr"`
+	}
+
+	type Bucket struct {
+		Name       string            `hcl:"name,attr"`
+		Versioning []Versioning      `hcl:"versioning,block"`
+		Tags       map[string]string `hcl:"tags,attr"`
+	}
+
+	var b Bucket
+	if err := raw.DecodeInto(&b); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if b.Name != "my-bucket" {
+		t.Fatalf("wrong name: %q", b.Name)
+	}
+	if len(b.Versioning) != 1 || !b.Versioning[0].Enabled {
+		t.Fatalf("wrong versioning: %#v", b.Versioning)
+	}
+	if b.Tags["Env"] != "prod" {
+		t.Fatalf("wrong tags: %#v", b.Tags)
+	}
+}
+
+func TestRawConfigDecodeInto_ctyLeaf(t *testing.T) {
+	raw, err := NewRawConfig(map[string]interface{}{
+		"instance_type": "t2.micro",
+		"count":         "3",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	type Instance struct {
+		// InstanceType is decoded via gocty as a cty.Value, deferring
+		// interpretation of the value entirely.
+		InstanceType cty.Value `hcl:"instance_type,attr" cty:"instance_type"`
+
+		// Count is decoded via gocty straight into a native Go int, rather
+		// than mapstructure's WeaklyTypedInput string->int coercion.
+		Count int `hcl:"count,attr" cty:"count"`
+	}
+
+	var i Instance
+	if err := raw.DecodeInto(&i); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if i.InstanceType.AsString() != "t2.micro" {
+		t.Fatalf("wrong instance_type: %#v", i.InstanceType)
+	}
+	if i.Count != 3 {
+		t.Fatalf("wrong count: %d", i.Count)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+)
+
+// loadTerraformHcl loads the "terraform" block(s) out of list into a single
+// *Terraform, merging repeated top-level blocks the same way the rest of
+// this loader merges other block kinds. It returns nil if list has no
+// "terraform" block at all.
+func loadTerraformHcl(list *hclast.ObjectList) (*Terraform, error) {
+	list = list.Filter("terraform")
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+
+	result := &Terraform{}
+	for _, item := range list.Items {
+		var raw map[string]interface{}
+		if err := hcl.DecodeObject(&raw, item.Val); err != nil {
+			return nil, fmt.Errorf("error reading terraform config: %s", err)
+		}
+		if v, ok := raw["required_version"].(string); ok {
+			result.RequiredVersion = v
+		}
+
+		obj, ok := item.Val.(*hclast.ObjectType)
+		if !ok {
+			return nil, fmt.Errorf("terraform block at %s: should be an object", item.Pos())
+		}
+
+		if backendItems := obj.List.Filter("backend"); len(backendItems.Items) > 0 {
+			backend, err := loadTerraformBackendHcl(backendItems.Items[0])
+			if err != nil {
+				return nil, err
+			}
+			result.Backend = backend
+		}
+
+		if cloudItems := obj.List.Filter("cloud"); len(cloudItems.Items) > 0 {
+			cloud, err := decodeCloudBlock(cloudItems.Items[0])
+			if err != nil {
+				return nil, err
+			}
+			result.Cloud = cloud
+		}
+
+		if err := validateBackendCloudExclusive(result.Backend, result.Cloud); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// loadTerraformBackendHcl decodes a `backend "TYPE" { ... }` block's
+// ast.ObjectItem into a Backend, computing its Hash so callers can detect
+// when the backend configuration has changed between runs. item comes from
+// ObjectList.Filter("backend"), which has already stripped the "backend"
+// key, so its only remaining key is the backend type.
+func loadTerraformBackendHcl(item *hclast.ObjectItem) (*Backend, error) {
+	if len(item.Keys) != 1 {
+		return nil, fmt.Errorf(
+			"backend block at %s: should be followed by exactly one string, the backend type",
+			item.Pos())
+	}
+	backendType := item.Keys[0].Token.Value().(string)
+
+	var raw map[string]interface{}
+	if err := hcl.DecodeObject(&raw, item.Val); err != nil {
+		return nil, fmt.Errorf("error reading backend config: %s", err)
+	}
+
+	rawConfig, err := NewRawConfig(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backend config: %s", err)
+	}
+
+	result := &Backend{
+		Type:      backendType,
+		RawConfig: rawConfig,
+	}
+	result.Hash = result.Rehash()
+
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+)
+
+func TestLoadTerraformHcl_cloud(t *testing.T) {
+	src := `
+terraform {
+  required_version = "> 0.12.0"
+
+  cloud {
+    organization = "my-org"
+    hostname     = "app.terraform.io"
+
+    workspaces {
+      name = "my-workspace"
+    }
+  }
+}
+`
+	f, err := hcl.Parse(src)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	tf, err := loadTerraformHcl(f.Node.(*hclast.ObjectList))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if tf == nil {
+		t.Fatal("expected a non-nil Terraform")
+	}
+
+	if tf.RequiredVersion != "> 0.12.0" {
+		t.Fatalf("wrong required_version: %q", tf.RequiredVersion)
+	}
+	if tf.Backend != nil {
+		t.Fatalf("expected no backend, got %#v", tf.Backend)
+	}
+	if tf.Cloud == nil {
+		t.Fatal("expected a decoded cloud block")
+	}
+	if tf.Cloud.Organization != "my-org" {
+		t.Fatalf("wrong organization: %q", tf.Cloud.Organization)
+	}
+	if tf.Cloud.Workspaces == nil || tf.Cloud.Workspaces.Name != "my-workspace" {
+		t.Fatalf("wrong workspaces: %#v", tf.Cloud.Workspaces)
+	}
+}
+
+func TestLoadTerraformHcl_backend(t *testing.T) {
+	src := `
+terraform {
+  backend "s3" {
+    bucket = "my-bucket"
+  }
+}
+`
+	f, err := hcl.Parse(src)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	tf, err := loadTerraformHcl(f.Node.(*hclast.ObjectList))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if tf.Backend == nil {
+		t.Fatal("expected a decoded backend block")
+	}
+	if tf.Backend.Type != "s3" {
+		t.Fatalf("wrong backend type: %q", tf.Backend.Type)
+	}
+	if tf.Backend.Hash == 0 {
+		t.Fatal("expected a non-zero backend hash")
+	}
+}
+
+func TestLoadTerraformHcl_backendAndCloudConflict(t *testing.T) {
+	src := `
+terraform {
+  backend "s3" {
+    bucket = "my-bucket"
+  }
+
+  cloud {
+    organization = "my-org"
+  }
+}
+`
+	f, err := hcl.Parse(src)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := loadTerraformHcl(f.Node.(*hclast.ObjectList)); err == nil {
+		t.Fatal("expected an error for a terraform block with both backend and cloud set")
+	}
+}
+
+func TestLoadTerraformHcl_none(t *testing.T) {
+	f, err := hcl.Parse(`resource "test_instance" "foo" {}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	tf, err := loadTerraformHcl(f.Node.(*hclast.ObjectList))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if tf != nil {
+		t.Fatalf("expected nil Terraform, got %#v", tf)
+	}
+}
@@ -0,0 +1,19 @@
+package schema
+
+import "fmt"
+
+// DecodeInto decodes this resource's raw configuration directly into
+// target, bypassing the usual Get/GetOk dotted-key access entirely. See
+// config.RawConfig.DecodeInto for the supported struct tags.
+//
+// Config is only available while there's a config to decode from (i.e.
+// during Create, Update, and a plan's Diff), not during Read, Delete, or
+// Exists, which only have the prior state; DecodeInto returns an error in
+// those cases.
+func (d *ResourceData) DecodeInto(target interface{}) error {
+	if d.config == nil {
+		return fmt.Errorf("no configuration available to decode (DecodeInto isn't usable from Read/Delete/Exists)")
+	}
+
+	return d.config.DecodeInto(target)
+}
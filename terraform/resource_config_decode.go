@@ -0,0 +1,17 @@
+package terraform
+
+import "fmt"
+
+// DecodeInto decodes the resource's raw configuration directly into
+// target; see config.RawConfig.DecodeInto for the supported struct tags
+// and behavior. It requires the ResourceConfig to have been built from a
+// config.RawConfig (true for any ResourceConfig terraform itself hands to
+// a provider), and returns an error if that's not the case, such as for a
+// ResourceConfig built by hand in a test.
+func (c *ResourceConfig) DecodeInto(target interface{}) error {
+	if c.raw == nil {
+		return fmt.Errorf("resource config has no raw config to decode from")
+	}
+
+	return c.raw.DecodeInto(target)
+}
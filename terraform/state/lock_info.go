@@ -0,0 +1,103 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	uuid "github.com/hashicorp/go-uuid"
+)
+
+// LockInfo stores lock metadata.
+//
+// Only Operation and Info are required to be set by the caller of Lock.
+// Most backends will set ID, Created, and Who.
+type LockInfo struct {
+	// Unique ID for the lock. NewLockInfo provides a random ID, but this
+	// may be overridden by the lock implementation. The final value if
+	// ID will be returned by the call to Lock.
+	ID string
+
+	// Terraform operation, provided by the caller.
+	Operation string
+
+	// Extra information to store with the lock, provided by the caller.
+	Info string
+
+	// Who is the user that requested the lock, filled in by default with
+	// the current user and host.
+	Who string
+
+	// Version is the Terraform version used to create the lock.
+	Version string
+
+	// Created is the time that the lock was taken.
+	Created time.Time
+
+	// Path is the path, or identifier, of the state being locked.
+	Path string
+}
+
+// Err returns the lock info formatted as an error.
+func (l *LockInfo) Err() error {
+	return fmt.Errorf("ID:        %s\nPath:      %s\nOperation: %s\nWho:       %s\nVersion:   %s\nCreated:   %s\nInfo:      %s",
+		l.ID, l.Path, l.Operation, l.Who, l.Version, l.Created, l.Info)
+}
+
+// String returns a string representation of the lock info.
+func (l *LockInfo) String() string {
+	js, err := json.Marshal(l)
+	if err != nil {
+		// should never happen
+		panic(err)
+	}
+	return string(js)
+}
+
+// Marshal returns a string json representation of the LockInfo.
+func (l *LockInfo) Marshal() []byte {
+	js, err := json.Marshal(l)
+	if err != nil {
+		panic(err)
+	}
+	return js
+}
+
+// NewLockInfo returns a LockInfo populated with a unique ID and the host's
+// best guess at the current user.
+func NewLockInfo() *LockInfo {
+	// this doesn't need to be cryptographically secure, just unique.
+	// Using math/rand alone is fine, but go-uuid is already a dependency
+	// of terraform via other packages, so reuse it here too.
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		// this of course shouldn't happen
+		panic(err)
+	}
+
+	info := &LockInfo{
+		ID:      id,
+		Who:     whoAmI(),
+		Created: time.Now().UTC(),
+	}
+	return info
+}
+
+// whoAmI returns a "user@host" string describing the local user, using
+// "unknown" for the user if it can't be determined and for the host if the
+// hostname can't be read.
+func whoAmI() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return fmt.Sprintf("unknown@%s", host)
+	}
+
+	return fmt.Sprintf("%s@%s", u.Username, host)
+}
@@ -0,0 +1,25 @@
+package remote
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// NewClientFromBackend builds the Client configured by a parsed
+// `backend "TYPE" { ... }` block, flattening its RawConfig into the
+// map[string]string each Factory expects (the same shape the legacy
+// backend-init pipeline has always passed down) and dispatching through
+// NewClient.
+func NewClientFromBackend(backend *config.Backend) (Client, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("no backend configured")
+	}
+
+	flat := make(map[string]string)
+	for k, v := range backend.RawConfig.Config() {
+		flat[k] = fmt.Sprintf("%v", v)
+	}
+
+	return NewClient(backend.Type, flat)
+}
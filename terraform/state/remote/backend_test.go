@@ -0,0 +1,47 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+func TestNewClientFromBackend(t *testing.T) {
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"address": "https://example.com/state",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	client, err := NewClientFromBackend(&config.Backend{
+		Type:      "http",
+		RawConfig: raw,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := client.(*httpClient); !ok {
+		t.Fatalf("expected an *httpClient, got %T", client)
+	}
+}
+
+func TestNewClientFromBackend_unknownType(t *testing.T) {
+	raw, err := config.NewRawConfig(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := NewClientFromBackend(&config.Backend{
+		Type:      "does-not-exist",
+		RawConfig: raw,
+	}); err == nil {
+		t.Fatal("expected an error for an unregistered backend type")
+	}
+}
+
+func TestNewClientFromBackend_nil(t *testing.T) {
+	if _, err := NewClientFromBackend(nil); err == nil {
+		t.Fatal("expected an error for a nil backend")
+	}
+}
@@ -0,0 +1,320 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform/state"
+)
+
+// httpClient implements Client (and ClientLocker) by speaking a minimal
+// REST-ish protocol to an arbitrary HTTP server:
+//
+//   GET    address -> 200 with the state as the body, 204 or 404 if no
+//                     state exists yet. Any other response is an error.
+//   POST   address?ID=<lock ID> -> persist the request body as the new
+//                                  state. The "ID" query parameter is only
+//                                  set while a lock is held.
+//   DELETE address -> purge the stored state.
+//
+// If lock_address is configured, the lock/unlock HTTP verbs (LOCK/UNLOCK by
+// default, overridable via lock_method/unlock_method) are used against
+// lock_address/unlock_address to acquire and release an advisory lock
+// before/after state operations. The request body for both is a JSON
+// state.LockInfo. A 409 or 423 response to a lock request is treated as
+// "already locked" and surfaced as a state.LockError; the same codes on an
+// unlock request are returned as a plain error.
+type httpClient struct {
+	URL          *url.URL
+	UpdateMethod string
+
+	LockURL, UnlockURL       *url.URL
+	LockMethod, UnlockMethod string
+
+	Username, Password string
+
+	Client *retryablehttp.Client
+
+	lockID       string
+	jsonLockInfo []byte
+}
+
+// httpFactory builds a Client from the flat config map produced by
+// the backend config loader. Recognized keys mirror the fields documented
+// for the "http" backend: address, update_method, lock_address,
+// lock_method, unlock_address, unlock_method, username, password,
+// skip_cert_verification, retry_max, retry_wait_min, retry_wait_max.
+func httpFactory(conf map[string]string) (Client, error) {
+	address, ok := conf["address"]
+	if !ok || address == "" {
+		return nil, fmt.Errorf("missing required \"address\" configuration")
+	}
+
+	addressURL, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse address url: %s", err)
+	}
+
+	client := &httpClient{
+		URL:          addressURL,
+		UpdateMethod: valueOr(conf["update_method"], "POST"),
+		Username:     conf["username"],
+		Password:     conf["password"],
+	}
+
+	if lockAddress, ok := conf["lock_address"]; ok && lockAddress != "" {
+		u, err := url.Parse(lockAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse lock_address url: %s", err)
+		}
+		client.LockURL = u
+		client.LockMethod = valueOr(conf["lock_method"], "LOCK")
+	}
+
+	if unlockAddress, ok := conf["unlock_address"]; ok && unlockAddress != "" {
+		u, err := url.Parse(unlockAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse unlock_address url: %s", err)
+		}
+		client.UnlockURL = u
+		client.UnlockMethod = valueOr(conf["unlock_method"], "UNLOCK")
+	}
+
+	skipCertVerification, err := parseBool(conf["skip_cert_verification"], false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid skip_cert_verification: %s", err)
+	}
+
+	retryMax, err := parseInt(conf["retry_max"], 2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry_max: %s", err)
+	}
+	retryWaitMin, err := parseSeconds(conf["retry_wait_min"], 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry_wait_min: %s", err)
+	}
+	retryWaitMax, err := parseSeconds(conf["retry_wait_max"], 30)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry_wait_max: %s", err)
+	}
+
+	client.Client = retryableClient(skipCertVerification, retryMax, retryWaitMin, retryWaitMax)
+
+	return client, nil
+}
+
+func valueOr(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func parseBool(v string, def bool) (bool, error) {
+	if v == "" {
+		return def, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+func parseInt(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func parseSeconds(v string, defSeconds int) (time.Duration, error) {
+	if v == "" {
+		return time.Duration(defSeconds) * time.Second, nil
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(secs) * time.Second, nil
+}
+
+// retryableClient builds the *retryablehttp.Client used for all requests.
+func retryableClient(skipCertVerification bool, retryMax int, retryWaitMin, retryWaitMax time.Duration) *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.RetryMax = retryMax
+	client.RetryWaitMin = retryWaitMin
+	client.RetryWaitMax = retryWaitMax
+	// retryablehttp logs to stderr by default, which is too noisy for a
+	// state backend, so its logger is disabled and retries aren't logged.
+	client.Logger = nil
+
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: skipCertVerification}
+	client.HTTPClient.Transport = transport
+
+	return client
+}
+
+func (c *httpClient) request(method string, u *url.URL, data []byte) (*http.Response, error) {
+	var body *bytes.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := retryablehttp.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make HTTP request: %s", err)
+	}
+
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	return c.Client.Do(req)
+}
+
+func (c *httpClient) Get() (*Payload, error) {
+	resp, err := c.request("GET", c.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// fall through
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("HTTP remote state endpoint returned %s", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote state: %s", err)
+	}
+
+	return &Payload{
+		Data: data,
+		MD5:  md5Sum(data),
+	}, nil
+}
+
+func (c *httpClient) Put(data []byte) error {
+	updateURL := *c.URL
+	if c.lockID != "" {
+		query := updateURL.Query()
+		query.Set("ID", c.lockID)
+		updateURL.RawQuery = query.Encode()
+	}
+
+	resp, err := c.request(c.UpdateMethod, &updateURL, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusConflict, http.StatusLocked:
+		return fmt.Errorf("state %q locked, refusing to overwrite", c.URL.String())
+	default:
+		return fmt.Errorf("failed to upload state: %s", resp.Status)
+	}
+}
+
+func (c *httpClient) Delete() error {
+	resp, err := c.request("DELETE", c.URL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	default:
+		return fmt.Errorf("failed to delete state: %s", resp.Status)
+	}
+}
+
+func (c *httpClient) Lock(info *state.LockInfo) (string, error) {
+	if c.LockURL == nil {
+		// Locking isn't configured for this backend; treat it as a
+		// successful no-op lock, same as other backends without
+		// locking support.
+		return "", nil
+	}
+
+	info.Path = c.URL.String()
+	raw := info.Marshal()
+
+	resp, err := c.request(c.LockMethod, c.LockURL, raw)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		c.lockID = info.ID
+		c.jsonLockInfo = raw
+		return info.ID, nil
+	case http.StatusUnauthorized:
+		return "", fmt.Errorf("HTTP remote state endpoint requires auth")
+	case http.StatusConflict, http.StatusLocked:
+		body, _ := ioutil.ReadAll(resp.Body)
+		existing := &state.LockInfo{}
+		if err := json.Unmarshal(body, existing); err != nil {
+			return "", &state.LockError{
+				Err: fmt.Errorf("HTTP remote state already locked, failed to unmarshal lock info: %s", err),
+			}
+		}
+		return "", &state.LockError{
+			Info: existing,
+			Err:  fmt.Errorf("HTTP remote state already locked: ID=%s", existing.ID),
+		}
+	default:
+		return "", fmt.Errorf("unexpected HTTP response %s during lock", resp.Status)
+	}
+}
+
+func (c *httpClient) Unlock(id string) error {
+	if c.UnlockURL == nil {
+		return nil
+	}
+
+	raw := c.jsonLockInfo
+	if raw == nil {
+		raw, _ = json.Marshal(&state.LockInfo{ID: id})
+	}
+
+	resp, err := c.request(c.UnlockMethod, c.UnlockURL, raw)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		c.lockID = ""
+		c.jsonLockInfo = nil
+		return nil
+	case http.StatusConflict, http.StatusLocked:
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unlock failed with %s: %s", resp.Status, body)
+	default:
+		return fmt.Errorf("unexpected HTTP response %s during unlock", resp.Status)
+	}
+}
+
+var _ ClientLocker = (*httpClient)(nil)
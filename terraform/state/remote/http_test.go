@@ -0,0 +1,238 @@
+package remote
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/state"
+)
+
+func TestHTTPClient(t *testing.T) {
+	handler := new(testHTTPHandler)
+	ts := httptest.NewServer(http.HandlerFunc(handler.handle))
+	defer ts.Close()
+
+	client, err := httpFactory(map[string]string{
+		"address":        ts.URL + "/state",
+		"lock_address":   ts.URL + "/lock",
+		"unlock_address": ts.URL + "/lock",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// no state yet
+	payload, err := client.Get()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if payload != nil {
+		t.Fatalf("expected no state, got: %#v", payload)
+	}
+
+	if err := client.Put([]byte(`{"foo":"bar"}`)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	payload, err = client.Get()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if payload == nil || string(payload.Data) != `{"foo":"bar"}` {
+		t.Fatalf("unexpected payload: %#v", payload)
+	}
+
+	if err := client.Delete(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestHTTPClient_MissingAddress(t *testing.T) {
+	if _, err := httpFactory(map[string]string{}); err == nil {
+		t.Fatal("expected error for missing address")
+	}
+}
+
+func TestHTTPClient_LockUnlock(t *testing.T) {
+	handler := new(testLockHandler)
+	ts := httptest.NewServer(http.HandlerFunc(handler.handle))
+	defer ts.Close()
+
+	c, err := httpFactory(map[string]string{
+		"address":        ts.URL + "/state",
+		"lock_address":   ts.URL + "/lock",
+		"unlock_address": ts.URL + "/lock",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := c.(*httpClient)
+
+	info := state.NewLockInfo()
+	info.Operation = "test"
+
+	id, err := client.Lock(info)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if id != info.ID {
+		t.Fatalf("expected lock ID %q, got %q", info.ID, id)
+	}
+
+	if err := client.Unlock(id); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestHTTPClient_LockConflict(t *testing.T) {
+	handler := new(testLockHandler)
+	ts := httptest.NewServer(http.HandlerFunc(handler.handle))
+	defer ts.Close()
+
+	conf := map[string]string{
+		"address":        ts.URL + "/state",
+		"lock_address":   ts.URL + "/lock",
+		"unlock_address": ts.URL + "/lock",
+	}
+
+	first, err := httpFactory(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	firstInfo := state.NewLockInfo()
+	firstInfo.Operation = "apply"
+	if _, err := first.(*httpClient).Lock(firstInfo); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	second, err := httpFactory(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	secondInfo := state.NewLockInfo()
+	secondInfo.Operation = "plan"
+
+	_, err = second.(*httpClient).Lock(secondInfo)
+	if err == nil {
+		t.Fatal("expected a lock conflict error")
+	}
+
+	lockErr, ok := err.(*state.LockError)
+	if !ok {
+		t.Fatalf("expected a *state.LockError, got %T: %s", err, err)
+	}
+	if lockErr.Info == nil || lockErr.Info.ID != firstInfo.ID {
+		t.Fatalf("expected conflict to report the existing lock's info, got %#v", lockErr.Info)
+	}
+}
+
+func TestHTTPClient_UnlockConflict(t *testing.T) {
+	handler := new(testLockHandler)
+	ts := httptest.NewServer(http.HandlerFunc(handler.handle))
+	defer ts.Close()
+
+	conf := map[string]string{
+		"address":        ts.URL + "/state",
+		"lock_address":   ts.URL + "/lock",
+		"unlock_address": ts.URL + "/lock",
+	}
+
+	locker, err := httpFactory(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	info := state.NewLockInfo()
+	info.Operation = "apply"
+	if _, err := locker.(*httpClient).Lock(info); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// A second, fresh client (that never locked, so has no cached lock
+	// info of its own) tries to unlock with an ID that doesn't match the
+	// held lock.
+	other, err := httpFactory(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := other.(*httpClient).Unlock("not-the-right-id"); err == nil {
+		t.Fatal("expected an error unlocking with the wrong ID")
+	}
+}
+
+// testHTTPHandler is a trivial in-memory implementation of the http backend
+// wire protocol, used to exercise httpClient end-to-end.
+type testHTTPHandler struct {
+	state []byte
+}
+
+func (h *testHTTPHandler) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		if h.state == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write(h.state)
+	case "POST":
+		buf, _ := ioutil.ReadAll(r.Body)
+		h.state = buf
+	case "DELETE":
+		h.state = nil
+	case "LOCK", "UNLOCK":
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// testLockHandler is a minimal stateful lock server: it tracks at most one
+// held lock's info, returns 423 with the existing lock's info on a
+// conflicting LOCK, and 409 on an UNLOCK whose ID doesn't match.
+type testLockHandler struct {
+	state []byte
+
+	locked   bool
+	lockInfo []byte
+}
+
+func (h *testLockHandler) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		if h.state == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write(h.state)
+	case "POST":
+		buf, _ := ioutil.ReadAll(r.Body)
+		h.state = buf
+	case "DELETE":
+		h.state = nil
+	case "LOCK":
+		if h.locked {
+			w.WriteHeader(http.StatusLocked)
+			w.Write(h.lockInfo)
+			return
+		}
+		buf, _ := ioutil.ReadAll(r.Body)
+		h.locked = true
+		h.lockInfo = buf
+		w.WriteHeader(http.StatusOK)
+	case "UNLOCK":
+		buf, _ := ioutil.ReadAll(r.Body)
+		var req state.LockInfo
+		var held state.LockInfo
+		if err := json.Unmarshal(buf, &req); err != nil || json.Unmarshal(h.lockInfo, &held) != nil || req.ID != held.ID {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		h.locked = false
+		h.lockInfo = nil
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
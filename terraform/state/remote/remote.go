@@ -0,0 +1,140 @@
+// Package remote provides State implementations that read and write
+// Terraform state to/from a remote store.
+package remote
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+
+	"github.com/hashicorp/terraform/state"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// Client is the interface that must be implemented for a remote state
+// driver. It supports the business logic of writing state but not the
+// underlying data storage.
+type Client interface {
+	Get() (*Payload, error)
+	Put([]byte) error
+	Delete() error
+}
+
+// ClientLocker is an optional interface that a Client can implement to
+// support locking of the remote state alongside reading/writing it.
+type ClientLocker interface {
+	Client
+	state.Locker
+}
+
+// Payload is the return value from the remote state storage.
+type Payload struct {
+	MD5  []byte
+	Data []byte
+}
+
+// Factory builds a Client from the flat configuration map produced by
+// decoding a `backend "TYPE" { ... }` block.
+type Factory func(map[string]string) (Client, error)
+
+// BuiltinClients maps each backend type name to the Factory that builds
+// its Client. The backend-init pipeline dispatches `backend "TYPE" { ... }`
+// blocks through NewClient, which looks the type up here.
+var BuiltinClients = map[string]Factory{
+	"http": httpFactory,
+}
+
+// NewClient builds the Client for the backend named backendType using
+// BuiltinClients, returning an error if no such backend is registered.
+func NewClient(backendType string, conf map[string]string) (Client, error) {
+	f, ok := BuiltinClients[backendType]
+	if !ok {
+		return nil, fmt.Errorf("unknown remote state backend type: %q", backendType)
+	}
+	return f(conf)
+}
+
+// State implements the state.State interface and uses a Client to store
+// and retrieve state.
+type State struct {
+	Client Client
+
+	state, readState *terraform.State
+}
+
+var _ state.State = (*State)(nil)
+
+// StateReader impl.
+func (s *State) State() *terraform.State {
+	return s.state.DeepCopy()
+}
+
+// WriteState impl.
+func (s *State) WriteState(state *terraform.State) error {
+	s.state = state.DeepCopy()
+	return nil
+}
+
+// RefreshState impl.
+func (s *State) RefreshState() error {
+	payload, err := s.Client.Get()
+	if err != nil {
+		return err
+	}
+	if payload == nil {
+		s.state = nil
+		s.readState = nil
+		return nil
+	}
+
+	state, err := terraform.ReadState(bytes.NewReader(payload.Data))
+	if err != nil {
+		return err
+	}
+
+	s.state = state
+	s.readState = state.DeepCopy()
+	return nil
+}
+
+// PersistState impl.
+func (s *State) PersistState() error {
+	if s.state == nil {
+		return nil
+	}
+
+	s.state.IncrementSerialMaybe(s.readState)
+
+	var buf bytes.Buffer
+	if err := terraform.WriteState(s.state, &buf); err != nil {
+		return err
+	}
+
+	if err := s.Client.Put(buf.Bytes()); err != nil {
+		return err
+	}
+
+	s.readState = s.state.DeepCopy()
+	return nil
+}
+
+// Lock calls Client.Lock if it's implemented.
+func (s *State) Lock(info *state.LockInfo) (string, error) {
+	if c, ok := s.Client.(ClientLocker); ok {
+		return c.Lock(info)
+	}
+	return "", nil
+}
+
+// Unlock calls Client.Unlock if it's implemented.
+func (s *State) Unlock(id string) error {
+	if c, ok := s.Client.(ClientLocker); ok {
+		return c.Unlock(id)
+	}
+	return nil
+}
+
+func md5Sum(b []byte) []byte {
+	sum := md5.Sum(b)
+	return sum[:]
+}
@@ -0,0 +1,90 @@
+// Package state exposes the interfaces and structs used to store state both
+// locally and remotely.
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// State is the interface that must be implemented by something that
+// persists state to some medium.
+type State interface {
+	// State returns the state for this. Other calls of this interface
+	// (WriteState, RefreshState) can replace it. remote.State returns a
+	// deep copy, so changes to the returned value only take effect
+	// through WriteState.
+	State() *terraform.State
+
+	// WriteState writes a new state. This state is immediately available
+	// to the State() function, but is not persisted until Persist() is
+	// called.
+	WriteState(*terraform.State) error
+
+	// RefreshState updates the state with the state from the remote
+	// server. This may not update the local copy of the state if the
+	// remote state is older than the local state.
+	RefreshState() error
+
+	// PersistState saves the state that was previously set via WriteState
+	// to durable storage.
+	PersistState() error
+
+	// Lock and Unlock are used to lock the state while being modified.
+	// Lock does not wait for a held lock to be released; it returns an
+	// error if the lock can't be obtained. Unlock releases a lock previously acquired with the
+	// matching LockInfo.ID.
+	Locker
+}
+
+// Locker is implemented by States that support state locking, so they can be
+// passed to the Lock and Unlock functions.
+//
+// States that support locking should embed Locker to implement State.
+type Locker interface {
+	// Lock attempts to obtain a lock on the state, returning a unique
+	// lock ID on success or an error if the lock is already held by
+	// someone else.
+	Lock(info *LockInfo) (string, error)
+
+	// Unlock releases a lock obtained by a corresponding call to Lock.
+	// The id must match the ID returned by Lock.
+	Unlock(id string) error
+}
+
+// LockDisabled implements Locker for states that don't support state
+// locking, returning errors for all locking operations.
+type LockDisabled struct{}
+
+func (l LockDisabled) Lock(info *LockInfo) (string, error) {
+	return "", fmt.Errorf("state locking is not supported for this backend")
+}
+
+func (l LockDisabled) Unlock(id string) error {
+	return fmt.Errorf("state locking is not supported for this backend")
+}
+
+// LockError is returned when a lock or unlock operation fails, for example
+// because the state is already locked by someone else. Info may be nil if
+// no information about the existing lock could be determined.
+type LockError struct {
+	Info *LockInfo
+	Err  error
+}
+
+func (e *LockError) Error() string {
+	var out string
+	if e.Err != nil {
+		out = e.Err.Error()
+	} else {
+		out = "state locked"
+	}
+
+	if e.Info != nil {
+		out = fmt.Sprintf("%s\nLock Info:\n  ID:        %s\n  Path:      %s\n  Operation: %s\n  Who:       %s\n  Created:   %s",
+			out, e.Info.ID, e.Info.Path, e.Info.Operation, e.Info.Who, e.Info.Created)
+	}
+
+	return out
+}
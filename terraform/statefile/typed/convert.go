@@ -0,0 +1,33 @@
+package typed
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/terraform/configs/hcl2shim"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SetValue replaces the instance's attributes with the flatmap
+// representation of v, so that subsequent calls to Write serialize the new
+// value back out as ordinary v3 flatmap state.
+//
+// v's type must match the schema's implied type; use Value's type as a
+// starting point when constructing a modified copy.
+func (i *Instance) SetValue(v cty.Value) error {
+	if i.schema == nil {
+		return fmt.Errorf("no schema known for this resource instance")
+	}
+
+	i.state.Attributes = hcl2shim.FlatmapValueFromHCL2(v)
+	return nil
+}
+
+// Write serializes the underlying terraform.State back out as ordinary v3
+// flatmap state. Any changes made via Instance.SetValue are included,
+// since TypedState never copies the underlying state out from under the
+// Instance views it hands out.
+func (s *TypedState) Write(w io.Writer) error {
+	return terraform.WriteState(s.state, w)
+}
@@ -0,0 +1,179 @@
+// Package typed wraps terraform.State in an API that exposes each resource
+// instance's attributes as a cty.Value instead of the raw flatmap
+// (map[string]string) that terraform.ResourceState stores on disk.
+// Instance.SetValue and TypedState.Write convert a value back to flatmap.
+//
+// Turning flatmap attributes like "foo.0" and "bar.w" into structured
+// values requires knowing the implied type of the resource, which is why
+// Load takes a ProviderSchemas: the schema tells us, for each resource
+// type, what the flatmap keys actually mean.
+package typed
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/configs/hcl2shim"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ProviderSchemas supplies the resource schema typed needs in order to
+// convert a resource's flatmap attributes into a cty.Value. Callers
+// typically build one from the schemas returned by the provider plugins
+// that produced the state being loaded.
+type ProviderSchemas interface {
+	// SchemaForResourceType returns the schema for the given resource
+	// type, or nil if no schema is known for it.
+	SchemaForResourceType(resourceType string) *configschema.Block
+}
+
+// TypedState is a typed view of a terraform.State. It is read-only except
+// through Instance.SetValue, which writes into the wrapped state.
+type TypedState struct {
+	state   *terraform.State
+	schemas ProviderSchemas
+}
+
+// Load reads a state file from r and wraps it as a TypedState, using
+// schemas to convert each resource instance's flatmap attributes into a
+// cty.Value.
+func Load(r io.Reader, schemas ProviderSchemas) (*TypedState, error) {
+	state, err := terraform.ReadState(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %s", err)
+	}
+
+	return &TypedState{
+		state:   state,
+		schemas: schemas,
+	}, nil
+}
+
+// Module returns a typed view of the module at addr, or nil if the state
+// has no such module.
+func (s *TypedState) Module(addr string) *Module {
+	ms := s.state.ModuleByPath(modulePath(addr))
+	if ms == nil {
+		return nil
+	}
+
+	return &Module{
+		addr:    addr,
+		state:   ms,
+		schemas: s.schemas,
+	}
+}
+
+// Module is a typed view of a single module's state.
+type Module struct {
+	addr    string
+	state   *terraform.ModuleState
+	schemas ProviderSchemas
+}
+
+// Resource returns a typed view of the resource at addr within this
+// module, or nil if the module has no such resource.
+func (m *Module) Resource(addr string) *Resource {
+	rs, ok := m.state.Resources[addr]
+	if !ok {
+		return nil
+	}
+
+	return &Resource{
+		addr:    addr,
+		state:   rs,
+		schemas: m.schemas,
+	}
+}
+
+// Resource is a typed view of a single resource's state: its primary
+// instance plus any deposed instances.
+type Resource struct {
+	addr    string
+	state   *terraform.ResourceState
+	schemas ProviderSchemas
+}
+
+// Instance returns a typed view of the instance keyed by k. k is either
+// "primary" (or ""), or the ID of one of the resource's deposed instances;
+// it returns nil if no instance matches that key.
+func (r *Resource) Instance(k string) *Instance {
+	var is *terraform.InstanceState
+	switch k {
+	case "", "primary":
+		is = r.state.Primary
+	default:
+		for _, d := range r.state.Deposed {
+			if d != nil && d.ID == k {
+				is = d
+				break
+			}
+		}
+	}
+	if is == nil {
+		return nil
+	}
+
+	var schema *configschema.Block
+	if r.schemas != nil {
+		schema = r.schemas.SchemaForResourceType(r.state.Type)
+	}
+
+	return &Instance{
+		state:  is,
+		schema: schema,
+	}
+}
+
+// Instance is a typed view of a single resource instance.
+type Instance struct {
+	state  *terraform.InstanceState
+	schema *configschema.Block
+}
+
+// Value returns the instance's attributes as a cty.Value, shaped according
+// to its resource schema's implied type. If no schema is known for the
+// instance's resource type, Value returns cty.DynamicVal.
+func (i *Instance) Value() cty.Value {
+	if i.schema == nil {
+		return cty.DynamicVal
+	}
+
+	v, err := hcl2shim.HCL2ValueFromFlatmap(i.state.Attributes, i.schema.ImpliedType())
+	if err != nil {
+		// A malformed flatmap shouldn't happen for state that came from
+		// a real apply, but fall back to a null value of the implied
+		// type rather than panicking on bad input.
+		return cty.NullVal(i.schema.ImpliedType())
+	}
+
+	return v
+}
+
+func modulePath(addr string) addrs.ModuleInstance {
+	if addr == "" || addr == "root" {
+		return addrs.RootModuleInstance
+	}
+
+	path := addrs.RootModuleInstance
+	for _, name := range splitModuleAddr(addr) {
+		path = path.Child(name, addrs.NoKey)
+	}
+	return path
+}
+
+func splitModuleAddr(addr string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(addr); i++ {
+		if addr[i] == '.' {
+			parts = append(parts, addr[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, addr[start:])
+	return parts
+}
@@ -0,0 +1,152 @@
+package typed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type testSchemas struct {
+	schemas map[string]*configschema.Block
+}
+
+func (s testSchemas) SchemaForResourceType(resourceType string) *configschema.Block {
+	return s.schemas[resourceType]
+}
+
+func TestTypedState(t *testing.T) {
+	schemas := testSchemas{
+		schemas: map[string]*configschema.Block{
+			"test_instance": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":  {Type: cty.String, Computed: true},
+					"ami": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	}
+
+	const raw = `
+{
+    "version": 3,
+    "modules": [
+        {
+            "path": ["root"],
+            "resources": {
+                "test_instance.foo": {
+                    "type": "test_instance",
+                    "primary": {
+                        "id": "i-abc123",
+                        "attributes": {
+                            "id": "i-abc123",
+                            "ami": "ami-1234"
+                        }
+                    }
+                }
+            }
+        }
+    ]
+}`
+
+	state, err := Load(strings.NewReader(raw), schemas)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	instance := state.Module("root").Resource("test_instance.foo").Instance("primary")
+	if instance == nil {
+		t.Fatal("expected an instance")
+	}
+
+	v := instance.Value()
+	if !v.Type().IsObjectType() {
+		t.Fatalf("expected object type, got %#v", v.Type())
+	}
+	if got := v.GetAttr("ami").AsString(); got != "ami-1234" {
+		t.Fatalf("wrong ami: %q", got)
+	}
+}
+
+func TestTypedState_noSchema(t *testing.T) {
+	const raw = `
+{
+    "version": 3,
+    "modules": [
+        {
+            "path": ["root"],
+            "resources": {
+                "test_instance.foo": {
+                    "type": "test_instance",
+                    "primary": {
+                        "id": "i-abc123",
+                        "attributes": {"id": "i-abc123"}
+                    }
+                }
+            }
+        }
+    ]
+}`
+
+	state, err := Load(strings.NewReader(raw), testSchemas{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	v := state.Module("root").Resource("test_instance.foo").Instance("primary").Value()
+	if v != cty.DynamicVal {
+		t.Fatalf("expected DynamicVal for unknown schema, got %#v", v)
+	}
+}
+
+func TestTypedState_deposedByID(t *testing.T) {
+	schemas := testSchemas{
+		schemas: map[string]*configschema.Block{
+			"test_instance": {
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	}
+
+	const raw = `
+{
+    "version": 3,
+    "modules": [
+        {
+            "path": ["root"],
+            "resources": {
+                "test_instance.foo": {
+                    "type": "test_instance",
+                    "primary": {"id": "i-current", "attributes": {"id": "i-current"}},
+                    "deposed": [
+                        {"id": "i-old-1", "attributes": {"id": "i-old-1"}},
+                        {"id": "i-old-2", "attributes": {"id": "i-old-2"}}
+                    ]
+                }
+            }
+        }
+    ]
+}`
+
+	state, err := Load(strings.NewReader(raw), schemas)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	res := state.Module("root").Resource("test_instance.foo")
+
+	second := res.Instance("i-old-2")
+	if second == nil {
+		t.Fatal("expected to find deposed instance i-old-2")
+	}
+	if got := second.Value().GetAttr("id").AsString(); got != "i-old-2" {
+		t.Fatalf("wrong deposed instance returned: got id %q, want i-old-2", got)
+	}
+
+	if res.Instance("i-does-not-exist") != nil {
+		t.Fatal("expected nil for an unknown deposed key")
+	}
+}
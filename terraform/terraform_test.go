@@ -18,7 +18,9 @@ import (
 	"github.com/hashicorp/terraform/helper/logging"
 )
 
-// This is the directory where our test fixtures are.
+// This is the directory where our test fixtures are. Fixtures are plain
+// Terraform configuration/state trees checked in under version control;
+// see the individual test helpers below for how they're loaded.
 const fixtureDir = "./test-fixtures"
 
 func TestMain(m *testing.M) {
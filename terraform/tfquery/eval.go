@@ -0,0 +1,100 @@
+package tfquery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/itchyny/gojq"
+)
+
+// Eval runs a jq expr against ast (as produced by LoadConfigDir or
+// LoadStateReader) and returns each emitted value.
+//
+// By default expr is evaluated with the embedded gojq engine. Pass
+// useSystemJQ to shell out to the "jq" binary on PATH instead, which is
+// occasionally useful for expressions that rely on a gojq/jq behavioral
+// difference or a jq module the embedded evaluator doesn't support.
+func Eval(ast interface{}, expr string, useSystemJQ bool) ([]interface{}, error) {
+	if useSystemJQ {
+		return evalSystemJQ(ast, expr)
+	}
+	return evalGojq(ast, expr)
+}
+
+// toGeneric round-trips ast through encoding/json so that any concrete Go
+// types in it (like tfquery.Body) become the plain map[string]interface{}/
+// []interface{}/string/float64/bool/nil shapes gojq operates on.
+func toGeneric(ast interface{}) (interface{}, error) {
+	raw, err := json.Marshal(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func evalGojq(ast interface{}, expr string) ([]interface{}, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %s", err)
+	}
+
+	generic, err := toGeneric(ast)
+	if err != nil {
+		return nil, fmt.Errorf("internal error normalizing AST: %s", err)
+	}
+
+	iter := query.Run(generic)
+	var results []interface{}
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		results = append(results, v)
+	}
+
+	return results, nil
+}
+
+func evalSystemJQ(ast interface{}, expr string) ([]interface{}, error) {
+	input, err := json.Marshal(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("jq", "-c", expr)
+	cmd.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("jq: %s: %s", err, stderr.String())
+	}
+
+	dec := json.NewDecoder(&stdout)
+	var results []interface{}
+	for {
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		results = append(results, v)
+	}
+
+	return results, nil
+}
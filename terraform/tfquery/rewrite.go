@@ -0,0 +1,179 @@
+package tfquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hclwrite"
+)
+
+var hclInitialPos = hcl.Pos{Line: 1, Column: 1, Byte: 0}
+
+// Rewrite runs expr (a jq filter over the whole AST, e.g. one built around
+// `.body.blocks[] |= ...`) and writes back any changed top-level block
+// attributes to their source files, using hclwrite so that everything else
+// in the file - comments, blank lines, unrelated attributes - is left
+// byte-for-byte as it was.
+//
+// Only attributes on top-level blocks (resource, data, variable, and so on
+// - not attributes nested inside a block like `tags { ... }`) can be
+// rewritten this way; expr is free to inspect nested blocks, but edits to
+// them are ignored. A changed attribute's new value must be either a string
+// containing valid HCL source for the replacement expression, or (to change
+// just one field of an object constructor attribute, as in
+// `.attributes.tags.Env = "\"prod\""`) a
+// nested object whose leaves are such strings - renderHCLValue turns it
+// back into a single HCL expression covering the whole attribute, since
+// tfquery never evaluates attribute values, only compares their source
+// text.
+func Rewrite(dir string, expr string) error {
+	before, err := LoadConfigDir(dir)
+	if err != nil {
+		return err
+	}
+
+	results, err := Eval(before, expr, false)
+	if err != nil {
+		return err
+	}
+	if len(results) != 1 {
+		return fmt.Errorf("rewrite expression must produce exactly one value, got %d", len(results))
+	}
+
+	beforeBlocks := before["body"].(Body).Blocks
+
+	var after struct {
+		Body Body `json:"body"`
+	}
+	raw, err := json.Marshal(results[0])
+	if err != nil {
+		return fmt.Errorf("internal error re-encoding query result: %s", err)
+	}
+	if err := json.Unmarshal(raw, &after); err != nil {
+		return fmt.Errorf("rewrite expression must produce a value shaped like the input AST: %s", err)
+	}
+
+	edits := map[string]map[int]map[string]interface{}{} // file -> index -> attr -> new value
+	for _, b := range after.Body.Blocks {
+		orig := findBlock(beforeBlocks, b.File, b.Index)
+		if orig == nil {
+			continue
+		}
+		for name, newVal := range b.Attributes {
+			if oldVal, ok := orig.Attributes[name]; !ok || !reflect.DeepEqual(oldVal, newVal) {
+				if edits[b.File] == nil {
+					edits[b.File] = map[int]map[string]interface{}{}
+				}
+				if edits[b.File][b.Index] == nil {
+					edits[b.File][b.Index] = map[string]interface{}{}
+				}
+				edits[b.File][b.Index][name] = newVal
+			}
+		}
+	}
+
+	for file, byIndex := range edits {
+		if err := applyEdits(file, byIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func findBlock(blocks []*Block, file string, index int) *Block {
+	for _, b := range blocks {
+		if b.File == file && b.Index == index {
+			return b
+		}
+	}
+	return nil
+}
+
+func applyEdits(file string, byIndex map[int]map[string]interface{}) error {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	f, diags := hclwrite.ParseConfig(src, file, hclInitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	blocks := f.Body().Blocks()
+	for index, attrs := range byIndex {
+		if index < 0 || index >= len(blocks) {
+			continue
+		}
+		body := blocks[index].Body()
+		for name, newVal := range attrs {
+			newSrc, err := renderHCLValue(newVal)
+			if err != nil {
+				return fmt.Errorf("%s: invalid replacement for %q: %s", file, name, err)
+			}
+			tokens, err := exprTokens(newSrc)
+			if err != nil {
+				return fmt.Errorf("%s: invalid replacement for %q: %s", file, name, err)
+			}
+			body.SetAttributeRaw(name, tokens)
+		}
+	}
+
+	return ioutil.WriteFile(file, f.Bytes(), 0644)
+}
+
+// renderHCLValue turns an attribute's new value - either a plain string of
+// HCL source (the common case) or a nested map[string]interface{} produced
+// by editing one field of an object constructor attribute (see
+// exprToGeneric) - back into a single HCL source expression that exprTokens
+// can parse. Object keys are rendered in sorted order for a deterministic
+// result; this does discard the original object's exact formatting, but
+// only for the one attribute that changed.
+func renderHCLValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fields := make([]string, 0, len(keys))
+		for _, k := range keys {
+			rendered, err := renderHCLValue(val[k])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, fmt.Sprintf("%s = %s", k, rendered))
+		}
+		return "{ " + strings.Join(fields, ", ") + " }", nil
+	default:
+		return "", fmt.Errorf("unsupported attribute value of type %T", v)
+	}
+}
+
+// exprTokens parses src as a standalone HCL expression (by embedding it in
+// a throwaway "_ = <src>" attribute) and returns its tokens, so it can be
+// spliced into an existing file with hclwrite.Body.SetAttributeRaw.
+func exprTokens(src string) (hclwrite.Tokens, error) {
+	synthetic := []byte("_ = " + src + "\n")
+	f, diags := hclwrite.ParseConfig(synthetic, "<rewrite-value>", hclInitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attr := f.Body().GetAttribute("_")
+	if attr == nil {
+		return nil, fmt.Errorf("not a valid expression")
+	}
+
+	return attr.Expr().BuildTokens(nil), nil
+}
@@ -0,0 +1,120 @@
+package tfquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/terraform/statefile/typed"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// LoadStateReader parses a terraform.tfstate file from r and returns its
+// resource instances as a query-able AST:
+//
+//	{
+//	  "resources": [
+//	    {"module": "root", "address": "aws_instance.web", "type": "aws_instance", "instances": {"primary": {...}}}
+//	  ]
+//	}
+//
+// Instance attribute trees come from the typed state API (schemas is
+// passed straight through to typed.Load), so nested objects/lists/numbers
+// query naturally instead of requiring dotted flatmap keys.
+//
+// r must support Seek, since the raw state is read once to drive the walk
+// over modules/resources and again (via typed.Load) to build typed values.
+func LoadStateReader(r io.ReadSeeker, schemas typed.ProviderSchemas) (map[string]interface{}, error) {
+	raw, err := terraform.ReadState(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to re-read state: %s", err)
+	}
+
+	typedState, err := typed.Load(r, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []map[string]interface{}
+	for _, ms := range raw.Modules {
+		moduleAddr := joinModulePath(ms.Path)
+
+		mod := typedState.Module(moduleAddr)
+		if mod == nil {
+			continue
+		}
+
+		displayModule := moduleAddr
+		if displayModule == "" {
+			displayModule = "root"
+		}
+
+		for addr, rs := range ms.Resources {
+			res := mod.Resource(addr)
+			if res == nil {
+				continue
+			}
+
+			instances := map[string]interface{}{}
+			if rs.Primary != nil {
+				v, err := valueToJSON(res.Instance("primary").Value())
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s", addr, err)
+				}
+				instances["primary"] = v
+			}
+
+			resources = append(resources, map[string]interface{}{
+				"module":    displayModule,
+				"address":   addr,
+				"type":      rs.Type,
+				"instances": instances,
+			})
+		}
+	}
+
+	return map[string]interface{}{"resources": resources}, nil
+}
+
+// joinModulePath converts a raw state module path (e.g. []string{"root"} or
+// []string{"root", "child"}) into the dotted addr typed.TypedState.Module
+// expects ("" for root, "child" or "child.grandchild" for nested modules).
+func joinModulePath(path []string) string {
+	if len(path) <= 1 {
+		return ""
+	}
+	out := path[1]
+	for _, p := range path[2:] {
+		out += "." + p
+	}
+	return out
+}
+
+// valueToJSON converts a cty.Value into plain Go data (map[string]interface{},
+// []interface{}, string, float64, bool, nil) so it can be handed to a jq
+// evaluator or encoding/json without any cty-awareness on the other end.
+// A null or wholly unknown value (such as the cty.DynamicVal an instance
+// without a known schema returns) becomes nil.
+func valueToJSON(v cty.Value) (interface{}, error) {
+	if v == cty.NilVal || !v.IsKnown() || v.IsNull() {
+		return nil, nil
+	}
+
+	raw, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,202 @@
+// Package tfquery builds a stable, JSON-shaped AST from Terraform
+// configuration and state, and lets callers run jq expressions against it.
+//
+// The AST is deliberately generic (maps, slices, and scalars) rather than
+// typed Go structs, since the whole point is to hand it to a jq evaluator:
+//
+//	{
+//	  "body": {
+//	    "blocks": [
+//	      {"type": "resource", "labels": ["aws_instance", "web"], "attributes": {...}, "body": {"blocks": [...]}}
+//	    ]
+//	  }
+//	}
+//
+// Attribute values in the config AST are kept as their literal HCL source
+// text rather than evaluated, since evaluating them correctly would need
+// the same variable/function context that only Terraform core has. State
+// attributes, by contrast, are already concrete, so the state AST (see
+// state.go) exposes them as a real typed tree built via
+// terraform/statefile/typed.
+package tfquery
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Block is one node of the config AST: an HCL block together with its
+// attribute source expressions and nested blocks.
+type Block struct {
+	Type   string   `json:"type"`
+	Labels []string `json:"labels,omitempty"`
+
+	// Attributes holds each attribute's value: an object constructor
+	// expression (`tags = { Env = "prod" }`) decodes into a nested
+	// map[string]interface{} so it can be navigated field-by-field
+	// (`.attributes.tags.Env`); anything else is kept as its literal HCL
+	// source text, since tfquery never evaluates expressions.
+	Attributes map[string]interface{} `json:"attributes"`
+	Body       Body                   `json:"body"`
+	Range      hcl.Range              `json:"range"`
+
+	// File and Index identify where this block sits among its file's
+	// top-level blocks. They're included in the AST (rather than kept
+	// Go-side only) so that Rewrite can still find a block's origin after
+	// it's been through a jq transform.
+	File  string `json:"file"`
+	Index int    `json:"index"`
+
+	// src points back at the parsed block this node came from. It isn't
+	// part of the JSON AST; Rewrite finds blocks by File and Index instead.
+	src *hclsyntax.Block
+}
+
+// Body holds the nested blocks of a Block (or of a file as a whole).
+type Body struct {
+	Blocks []*Block `json:"blocks"`
+}
+
+// LoadConfigDir parses every *.tf file in dir and returns a single merged
+// AST, suitable for querying with Eval. JSON-form config (*.tf.json) isn't
+// supported here since it has no comments/formatting to preserve, and is
+// more naturally queried by unmarshaling it directly with a generic jq
+// tool.
+func LoadConfigDir(dir string) (map[string]interface{}, error) {
+	files, err := configFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var root Body
+	parser := hclparse.NewParser()
+	for _, path := range files {
+		blocks, err := loadConfigFile(parser, path)
+		if err != nil {
+			return nil, err
+		}
+		root.Blocks = append(root.Blocks, blocks...)
+	}
+
+	return map[string]interface{}{"body": root}, nil
+}
+
+func configFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func loadConfigFile(parser *hclparse.Parser, path string) ([]*Block, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", path, err)
+	}
+
+	f, diags := parser.ParseHCL(src, path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("%s: unexpected body implementation %T", path, f.Body)
+	}
+
+	return topLevelBlocksToAST(body.Blocks, path, src), nil
+}
+
+// topLevelBlocksToAST builds AST nodes for a file's top-level blocks,
+// recording their file/index so Rewrite can find them again later. Nested
+// blocks are built the same way but with Index -1, since Rewrite only
+// supports editing attributes on top-level blocks (resource, data,
+// variable, and so on) for now.
+func topLevelBlocksToAST(blocks []*hclsyntax.Block, file string, src []byte) []*Block {
+	out := make([]*Block, 0, len(blocks))
+	for i, b := range blocks {
+		out = append(out, blockToAST(b, file, i, src))
+	}
+	return out
+}
+
+func blockToAST(b *hclsyntax.Block, file string, index int, src []byte) *Block {
+	attrs := make(map[string]interface{}, len(b.Body.Attributes))
+	for name, attr := range b.Body.Attributes {
+		attrs[name] = exprToGeneric(attr.Expr, src)
+	}
+
+	nested := make([]*Block, 0, len(b.Body.Blocks))
+	for _, child := range b.Body.Blocks {
+		nested = append(nested, blockToAST(child, file, -1, src))
+	}
+
+	return &Block{
+		Type:       b.Type,
+		Labels:     b.Labels,
+		Attributes: attrs,
+		Body:       Body{Blocks: nested},
+		Range:      b.DefRange(),
+		File:       file,
+		Index:      index,
+		src:        b,
+	}
+}
+
+// exprToGeneric decodes expr into a jq-navigable value. An object
+// constructor expression becomes a nested map[string]interface{} keyed by
+// its literal attribute names; anything else - including an object
+// constructor with a computed key, which can't be represented as a plain
+// object field - is kept as its literal HCL source text.
+func exprToGeneric(expr hclsyntax.Expression, src []byte) interface{} {
+	obj, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return string(expr.Range().SliceBytes(src))
+	}
+
+	values := make(map[string]interface{}, len(obj.Items))
+	for _, item := range obj.Items {
+		key, ok := objectKeyName(item.KeyExpr, src)
+		if !ok {
+			return string(expr.Range().SliceBytes(src))
+		}
+		values[key] = exprToGeneric(item.ValueExpr, src)
+	}
+	return values
+}
+
+// objectKeyName extracts an object constructor key's literal name. Keys are
+// usually bare identifiers (wrapped in an ObjectConsKeyExpr around a
+// ScopeTraversalExpr) or quoted strings (a single-part TemplateExpr); it
+// returns ok=false for anything else, i.e. an interpolated or otherwise
+// computed key.
+func objectKeyName(keyExpr hclsyntax.Expression, src []byte) (string, bool) {
+	if wrapped, ok := keyExpr.(*hclsyntax.ObjectConsKeyExpr); ok {
+		keyExpr = wrapped.Wrapped
+	}
+
+	switch e := keyExpr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		if len(e.Traversal) == 1 {
+			if root, ok := e.Traversal[0].(hcl.TraverseRoot); ok {
+				return root.Name, true
+			}
+		}
+	case *hclsyntax.TemplateExpr:
+		if len(e.Parts) == 1 {
+			if lit, ok := e.Parts[0].(*hclsyntax.LiteralValueExpr); ok && lit.Val.Type() == cty.String {
+				return lit.Val.AsString(), true
+			}
+		}
+	}
+	return "", false
+}
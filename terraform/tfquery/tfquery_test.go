@@ -0,0 +1,132 @@
+package tfquery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `
+resource "aws_instance" "web" {
+  ami           = "ami-1234"
+  instance_type = "t2.micro"
+
+  tags {
+    Env = "dev"
+  }
+}
+`
+
+func writeTestConfig(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "tfquery")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.tf"), []byte(testConfig), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return dir
+}
+
+func TestLoadConfigDirAndEval(t *testing.T) {
+	dir := writeTestConfig(t)
+
+	ast, err := LoadConfigDir(dir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	results, err := Eval(ast, `.body.blocks[] | select(.type=="resource" and .labels[0]=="aws_instance") | .attributes.instance_type`, false)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(results) != 1 || results[0] != `"t2.micro"` {
+		t.Fatalf("unexpected result: %#v", results)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	dir := writeTestConfig(t)
+
+	expr := `.body.blocks[] |= if .type == "resource" then .attributes.ami = "\"ami-9999\"" else . end`
+	if err := Rewrite(dir, expr); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if got := string(out); !strings.Contains(got, `"ami-9999"`) {
+		t.Fatalf("expected rewritten ami, got:\n%s", got)
+	}
+}
+
+const testConfigWithTagsAttribute = `
+resource "aws_instance" "web" {
+  ami           = "ami-1234"
+  instance_type = "t2.micro"
+
+  tags = {
+    Env = "dev"
+  }
+}
+`
+
+func writeTestConfigWithTagsAttribute(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "tfquery")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.tf"), []byte(testConfigWithTagsAttribute), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return dir
+}
+
+func TestLoadConfigDir_nestedAttribute(t *testing.T) {
+	dir := writeTestConfigWithTagsAttribute(t)
+
+	ast, err := LoadConfigDir(dir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	results, err := Eval(ast, `.body.blocks[] | select(.type=="resource") | .attributes.tags.Env`, false)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 1 || results[0] != `"dev"` {
+		t.Fatalf("unexpected result: %#v", results)
+	}
+}
+
+// TestRewrite_nestedAttribute exercises rewriting a single field of an
+// object constructor attribute rather than the whole attribute.
+func TestRewrite_nestedAttribute(t *testing.T) {
+	dir := writeTestConfigWithTagsAttribute(t)
+
+	expr := `.body.blocks[] |= if .type=="resource" then .attributes.tags.Env = "\"prod\"" else . end`
+	if err := Rewrite(dir, expr); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if got := string(out); !strings.Contains(got, `"prod"`) {
+		t.Fatalf("expected rewritten tags.Env, got:\n%s", got)
+	}
+}